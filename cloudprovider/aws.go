@@ -0,0 +1,38 @@
+package cloudprovider
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// awsProvisioner allocates an ELB/ALB per Ingress.
+type awsProvisioner struct {
+	clusterUID string
+}
+
+func newAWSProvisioner(clusterUID string) *awsProvisioner {
+	return &awsProvisioner{clusterUID: clusterUID}
+}
+
+// EnsureLB would reconcile the AWS ELB/ALB for ing and return its DNS
+// hostname. Actually calling the AWS API is not implemented yet, so this
+// fails loudly rather than reporting an empty address silently: running
+// with --cloud-provider=aws today would otherwise look successful while
+// never provisioning anything.
+func (p *awsProvisioner) EnsureLB(ing *networkingv1.Ingress) ([]LBIngress, error) {
+	return nil, errors.Errorf("aws load balancer provisioning is not implemented; resource %s would need an ELB/ALB in the AWS API", p.resourceName(ing))
+}
+
+// GC would delete AWS load balancer resources for any Ingress not in
+// activeIngresses. Since EnsureLB never successfully provisions anything
+// yet, there is nothing to clean up; this stays a no-op until EnsureLB is
+// implemented.
+func (p *awsProvisioner) GC(activeIngresses []string) error {
+	return nil
+}
+
+func (p *awsProvisioner) resourceName(ing *networkingv1.Ingress) string {
+	return fmt.Sprintf("k8s-%s-%s-%s", p.clusterUID, ing.Namespace, ing.Name)
+}
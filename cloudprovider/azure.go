@@ -0,0 +1,39 @@
+package cloudprovider
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// azureProvisioner allocates an Azure Application Gateway / Load Balancer
+// frontend IP per Ingress.
+type azureProvisioner struct {
+	clusterUID string
+}
+
+func newAzureProvisioner(clusterUID string) *azureProvisioner {
+	return &azureProvisioner{clusterUID: clusterUID}
+}
+
+// EnsureLB would reconcile the Azure load balancer frontend for ing and
+// return its allocated public IP. Actually calling the Azure API is not
+// implemented yet, so this fails loudly rather than reporting an empty
+// address silently: running with --cloud-provider=azure today would
+// otherwise look successful while never provisioning anything.
+func (p *azureProvisioner) EnsureLB(ing *networkingv1.Ingress) ([]LBIngress, error) {
+	return nil, errors.Errorf("azure load balancer provisioning is not implemented; resource %s would need a frontend ip and load balancing rule in the Azure API", p.resourceName(ing))
+}
+
+// GC would delete Azure load balancer resources for any Ingress not in
+// activeIngresses. Since EnsureLB never successfully provisions anything
+// yet, there is nothing to clean up; this stays a no-op until EnsureLB is
+// implemented.
+func (p *azureProvisioner) GC(activeIngresses []string) error {
+	return nil
+}
+
+func (p *azureProvisioner) resourceName(ing *networkingv1.Ingress) string {
+	return fmt.Sprintf("k8s-%s-%s-%s", p.clusterUID, ing.Namespace, ing.Name)
+}
@@ -0,0 +1,64 @@
+// Package cloudprovider lets the ingress controller provision a cloud load
+// balancer per Ingress, in addition to writing Gloo config, so that Gloo
+// can be used the same way GCE/AWS/Azure's own ingress controllers are.
+package cloudprovider
+
+import (
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Name identifies a supported cloud provider, as passed to --cloud-provider.
+type Name string
+
+const (
+	None  Name = "none"
+	GCE   Name = "gce"
+	AWS   Name = "aws"
+	Azure Name = "azure"
+)
+
+// LBIngress is a single address a provisioned load balancer is reachable
+// at, equivalent in shape to corev1.LoadBalancerIngress but decoupled from
+// any particular cloud SDK's types.
+type LBIngress struct {
+	IP       string
+	Hostname string
+}
+
+// LBProvisioner allocates and tears down cloud load balancer resources
+// (forwarding rules, target proxies, certs, ...) on behalf of Ingresses.
+// Implementations must be safe to call concurrently.
+type LBProvisioner interface {
+	// EnsureLB allocates or updates whatever cloud resources are needed to
+	// route traffic to ing, returning the address(es) it's reachable at.
+	EnsureLB(ing *networkingv1.Ingress) ([]LBIngress, error)
+
+	// GC removes cloud resources for any previously-provisioned Ingress
+	// not present in activeIngresses (each a "namespace/name" string).
+	GC(activeIngresses []string) error
+}
+
+// New constructs the LBProvisioner named by name. clusterUID should come
+// from GetClusterUID so that generated resource names are stable across
+// controller restarts and distinct across clusters sharing a project.
+func New(name Name, clusterUID string) (LBProvisioner, error) {
+	switch name {
+	case "", None:
+		return noopProvisioner{}, nil
+	case GCE:
+		return newGCEProvisioner(clusterUID), nil
+	case AWS:
+		return newAWSProvisioner(clusterUID), nil
+	case Azure:
+		return newAzureProvisioner(clusterUID), nil
+	}
+	return nil, errors.Errorf("unsupported --cloud-provider %q, must be one of [none, gce, aws, azure]", name)
+}
+
+// noopProvisioner is used when no cloud provider is configured: the
+// controller writes Gloo config only, same as before this package existed.
+type noopProvisioner struct{}
+
+func (noopProvisioner) EnsureLB(*networkingv1.Ingress) ([]LBIngress, error) { return nil, nil }
+func (noopProvisioner) GC([]string) error                                   { return nil }
@@ -0,0 +1,62 @@
+package cloudprovider
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterUIDConfigMapName is the configmap cloud LB resource names are
+// derived from, named after ingress-gce's equivalent "ingress-uid" map so
+// operators migrating from it see a familiar object.
+const ClusterUIDConfigMapName = "ingress-uid"
+
+const clusterUIDDataKey = "uid"
+
+// GetClusterUID returns a long-lived identifier for this cluster,
+// persisted in a configmap in namespace so that cloud LB resource names
+// stay stable across controller restarts, and distinct clusters sharing a
+// cloud project don't collide on generated names. Mirrors ingress-gce's
+// getClusterUID.
+func GetClusterUID(kube kubernetes.Interface, namespace string) (string, error) {
+	cm, err := kube.CoreV1().ConfigMaps(namespace).Get(ClusterUIDConfigMapName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if uid := cm.Data[clusterUIDDataKey]; uid != "" {
+			return uid, nil
+		}
+	case k8serrors.IsNotFound(err):
+		cm = nil
+	default:
+		return "", errors.Wrap(err, "looking up cluster uid configmap")
+	}
+
+	uid := string(uuid.NewUUID())
+
+	if cm != nil {
+		cm.Data = map[string]string{clusterUIDDataKey: uid}
+		if _, err := kube.CoreV1().ConfigMaps(namespace).Update(cm); err != nil {
+			return "", errors.Wrap(err, "persisting cluster uid")
+		}
+		return uid, nil
+	}
+
+	newCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterUIDConfigMapName,
+			Namespace: namespace,
+		},
+		Data: map[string]string{clusterUIDDataKey: uid},
+	}
+	if _, err := kube.CoreV1().ConfigMaps(namespace).Create(newCM); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			// Lost a create race with another replica; re-read its value.
+			return GetClusterUID(kube, namespace)
+		}
+		return "", errors.Wrap(err, "creating cluster uid configmap")
+	}
+	return uid, nil
+}
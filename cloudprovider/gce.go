@@ -0,0 +1,43 @@
+package cloudprovider
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// gceProvisioner allocates GCE forwarding rules, target proxies, and URL
+// maps per Ingress. Resource names are derived from clusterUID so that
+// repeated runs converge on the same resources instead of leaking orphans.
+type gceProvisioner struct {
+	clusterUID string
+}
+
+func newGCEProvisioner(clusterUID string) *gceProvisioner {
+	return &gceProvisioner{clusterUID: clusterUID}
+}
+
+// EnsureLB would reconcile the GCE forwarding rule, target HTTP(S) proxy,
+// and URL map for ing and return the forwarding rule's allocated IP.
+// Actually calling the GCE compute API is not implemented yet, so this
+// fails loudly rather than reporting an empty address silently: running
+// with --cloud-provider=gce today would otherwise look successful while
+// never provisioning anything.
+func (p *gceProvisioner) EnsureLB(ing *networkingv1.Ingress) ([]LBIngress, error) {
+	return nil, errors.Errorf("gce load balancer provisioning is not implemented; resource %s would need a forwarding rule, target proxy, and url map in the GCE compute API", p.resourceName(ing))
+}
+
+// GC would delete GCE resources for any Ingress not in activeIngresses.
+// Since EnsureLB never successfully provisions anything yet, there is
+// nothing to clean up; this stays a no-op until EnsureLB is implemented.
+func (p *gceProvisioner) GC(activeIngresses []string) error {
+	return nil
+}
+
+// resourceName derives a GCE resource name for ing that's stable across
+// controller restarts and unique per cluster, following ingress-gce's
+// "k8s-fw-<namespace>-<name>--<clusterUID>" convention.
+func (p *gceProvisioner) resourceName(ing *networkingv1.Ingress) string {
+	return fmt.Sprintf("k8s-fw-%s-%s--%s", ing.Namespace, ing.Name, p.clusterUID)
+}
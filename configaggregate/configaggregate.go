@@ -0,0 +1,24 @@
+// Package configaggregate gives the ingress and gateway-api translators a
+// shared, deterministic naming scheme so their generated Gloo config
+// objects can be written to the same storage.Interface without colliding,
+// mirroring how Istio's configcontroller.go multiplexes config sources.
+package configaggregate
+
+import "fmt"
+
+// Source identifies which translator produced a piece of Gloo config.
+type Source string
+
+const (
+	SourceIngress Source = "ing"
+	SourceGateway Source = "gw"
+)
+
+// Name returns the Gloo config object name to use for a resource
+// translated from namespace/name by source. Namespacing by source prevents
+// the ingress and gateway-api translators from generating the same name
+// for two logically distinct objects (e.g. an Ingress and an HTTPRoute
+// that both happen to be named "foo" in namespace "bar").
+func Name(source Source, namespace, name string) string {
+	return fmt.Sprintf("%s-%s-%s", source, namespace, name)
+}
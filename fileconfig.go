@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	fileWatchModePoll    = "poll"
+	fileWatchModeInotify = "inotify"
+)
+
+// parseFileFormats splits the --file.formats flag into the set of file
+// extensions (without the leading dot) createStorageClient will accept
+// in --file.config.dir.
+func parseFileFormats(flag string) (map[string]bool, error) {
+	formats := map[string]bool{}
+	for _, f := range strings.Split(flag, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		formats[f] = true
+	}
+	if len(formats) == 0 {
+		return nil, errors.New("--file.formats must list at least one format")
+	}
+	return formats, nil
+}
+
+// validateFileWatchMode rejects anything but the two supported
+// --file.watch-mode values.
+func validateFileWatchMode(mode string) error {
+	switch mode {
+	case fileWatchModePoll, fileWatchModeInotify:
+		return nil
+	}
+	return errors.Errorf("unsupported --file.watch-mode %q, must be one of [%v, %v]", mode, fileWatchModePoll, fileWatchModeInotify)
+}
+
+// validateConfigDirFormats walks dir and rejects any file whose extension
+// isn't in formats, so a typo'd or unsupported file dropped in by a
+// git-sync sidecar fails fast with a clear error instead of being silently
+// ignored (or mis-parsed) by the underlying config watcher. Dotfiles and
+// dot-directories (.git, .gitignore, git-sync's own ..data symlink target,
+// ...) are skipped rather than validated, since they're metadata the sync
+// mechanism itself owns, not config.
+func validateConfigDirFormats(dir string, formats map[string]bool) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if !formats[ext] {
+			return errors.Errorf("unsupported file extension %q for %v; --file.formats allows [%v]", ext, path, strings.Join(formatsList(formats), ", "))
+		}
+		return nil
+	})
+}
+
+func formatsList(formats map[string]bool) []string {
+	out := make([]string, 0, len(formats))
+	for f := range formats {
+		out = append(out, f)
+	}
+	return out
+}
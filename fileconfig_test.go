@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFileFormats(t *testing.T) {
+	cases := []struct {
+		name    string
+		flag    string
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "single format",
+			flag: "yaml",
+			want: map[string]bool{"yaml": true},
+		},
+		{
+			name: "multiple formats, mixed case and spacing",
+			flag: " YAML ,json",
+			want: map[string]bool{"yaml": true, "json": true},
+		},
+		{
+			name:    "empty flag is an error",
+			flag:    "",
+			wantErr: true,
+		},
+		{
+			name:    "only empty entries is an error",
+			flag:    " , ,",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseFileFormats(tc.flag)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("parseFileFormats(%q) error = %v, wantErr %v", tc.flag, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("parseFileFormats(%q) = %v, want %v", tc.flag, got, tc.want)
+			}
+			for k := range tc.want {
+				if !got[k] {
+					t.Errorf("parseFileFormats(%q) missing %q", tc.flag, k)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFileWatchMode(t *testing.T) {
+	if err := validateFileWatchMode(fileWatchModePoll); err != nil {
+		t.Errorf("validateFileWatchMode(poll) = %v, want nil", err)
+	}
+	if err := validateFileWatchMode(fileWatchModeInotify); err != nil {
+		t.Errorf("validateFileWatchMode(inotify) = %v, want nil", err)
+	}
+	if err := validateFileWatchMode("bogus"); err == nil {
+		t.Error("validateFileWatchMode(bogus) = nil, want error")
+	}
+}
+
+func TestValidateConfigDirFormats(t *testing.T) {
+	dir := t.TempDir()
+	formats := map[string]bool{"yaml": true}
+
+	if err := os.WriteFile(filepath.Join(dir, "virtualservice.yaml"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateConfigDirFormats(dir, formats); err != nil {
+		t.Fatalf("validateConfigDirFormats with only a valid file = %v, want nil", err)
+	}
+
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.MkdirAll(gitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte("[core]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateConfigDirFormats(dir, formats); err != nil {
+		t.Fatalf("validateConfigDirFormats with a .git dir present = %v, want nil", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateConfigDirFormats(dir, formats); err == nil {
+		t.Error("validateConfigDirFormats with an unsupported extension present = nil, want error")
+	}
+}
@@ -0,0 +1,225 @@
+// Package gateway translates Gateway API (gateway.networking.k8s.io)
+// resources into Gloo config, as an alternative to the legacy Ingress
+// translation performed by the ingress package.
+package gateway
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	gatewayapi "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
+
+	"github.com/solo-io/gloo-storage"
+)
+
+// Controller watches GatewayClass, Gateway, HTTPRoute, and ReferenceGrant
+// resources and translates the ones it owns into the same Gloo
+// VirtualServices/Upstreams the ingress package produces.
+type Controller struct {
+	store storage.Interface
+
+	gatewayClassName string
+
+	gatewayInformer  cache.SharedIndexInformer
+	classInformer    cache.SharedIndexInformer
+	routeInformer    cache.SharedIndexInformer
+	refGrantInformer cache.SharedIndexInformer
+
+	errs chan error
+}
+
+// DefaultGatewayClassController is the controller string a GatewayClass
+// must reference in spec.controllerName for this controller to claim its
+// Gateways, mirroring ingress.DefaultIngressClassController.
+const DefaultGatewayClassController = "gloo.solo.io/gateway-controller"
+
+// NewController creates a gateway-api Controller. It watches all four
+// Gateway API resource kinds cluster-wide and translates Gateways whose
+// GatewayClass references DefaultGatewayClassController.
+func NewController(cfg *rest.Config, store storage.Interface, syncFrequency time.Duration) (*Controller, error) {
+	client, err := gatewayclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gateway-api clientset")
+	}
+
+	c := &Controller{
+		store:            store,
+		gatewayClassName: DefaultGatewayClassController,
+		errs:             make(chan error),
+	}
+
+	c.classInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1().GatewayClasses().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1().GatewayClasses().Watch(options)
+			},
+		},
+		&gatewayapi.GatewayClass{}, syncFrequency, cache.Indexers{},
+	)
+
+	c.gatewayInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1().Gateways(metav1.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1().Gateways(metav1.NamespaceAll).Watch(options)
+			},
+		},
+		&gatewayapi.Gateway{}, syncFrequency, cache.Indexers{},
+	)
+
+	c.routeInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1().HTTPRoutes(metav1.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1().HTTPRoutes(metav1.NamespaceAll).Watch(options)
+			},
+		},
+		&gatewayapi.HTTPRoute{}, syncFrequency, cache.Indexers{},
+	)
+
+	c.refGrantInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1beta1().ReferenceGrants(metav1.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+				return client.GatewayV1beta1().ReferenceGrants(metav1.NamespaceAll).Watch(options)
+			},
+		},
+		&gatewayapi.ReferenceGrant{}, syncFrequency, cache.Indexers{},
+	)
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.sync() },
+		UpdateFunc: func(old, new interface{}) { c.sync() },
+		DeleteFunc: func(obj interface{}) { c.sync() },
+	}
+	c.gatewayInformer.AddEventHandler(handler)
+	c.routeInformer.AddEventHandler(handler)
+	c.refGrantInformer.AddEventHandler(handler)
+
+	return c, nil
+}
+
+// Run starts the informers and blocks until stop is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	go c.classInformer.Run(stop)
+	go c.gatewayInformer.Run(stop)
+	go c.routeInformer.Run(stop)
+	go c.refGrantInformer.Run(stop)
+	<-stop
+}
+
+// Error returns a channel of errors encountered while translating
+// Gateway API resources into Gloo config.
+func (c *Controller) Error() <-chan error {
+	return c.errs
+}
+
+// sync re-translates every HTTPRoute attached to a Gateway this controller
+// owns into Gloo config.
+func (c *Controller) sync() {
+	for _, obj := range c.routeInformer.GetStore().List() {
+		route, ok := obj.(*gatewayapi.HTTPRoute)
+		if !ok || !c.ownsRoute(route) {
+			continue
+		}
+		if err := c.translateRoute(route); err != nil {
+			c.errs <- errors.Wrap(err, "translating httproute to gloo config")
+		}
+	}
+}
+
+// ownsRoute reports whether route is attached to a Gateway whose
+// GatewayClass references this controller.
+func (c *Controller) ownsRoute(route *gatewayapi.HTTPRoute) bool {
+	for _, ref := range route.Spec.ParentRefs {
+		gwKey := route.Namespace + "/" + string(ref.Name)
+		if ref.Namespace != nil {
+			gwKey = string(*ref.Namespace) + "/" + string(ref.Name)
+		}
+		obj, exists, err := c.gatewayInformer.GetStore().GetByKey(gwKey)
+		if err != nil || !exists {
+			continue
+		}
+		gw, ok := obj.(*gatewayapi.Gateway)
+		if !ok {
+			continue
+		}
+		if c.classControllerMatches(string(gw.Spec.GatewayClassName)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) classControllerMatches(className string) bool {
+	obj, exists, err := c.classInformer.GetStore().GetByKey(className)
+	if err != nil || !exists {
+		return false
+	}
+	class, ok := obj.(*gatewayapi.GatewayClass)
+	if !ok {
+		return false
+	}
+	return string(class.Spec.ControllerName) == c.gatewayClassName
+}
+
+const coreGroup = ""
+const httpRouteGroupKind = "HTTPRoute"
+const serviceKind = "Service"
+const gatewayGroup = "gateway.networking.k8s.io"
+
+// backendRefAllowed reports whether ref may be translated into a route,
+// requiring a ReferenceGrant when it names a Service in a different
+// namespace than the HTTPRoute itself, per the Gateway API spec's
+// cross-namespace reference rules.
+func (c *Controller) backendRefAllowed(routeNamespace string, ref gatewayapi.HTTPBackendRef) bool {
+	if ref.Namespace == nil || string(*ref.Namespace) == routeNamespace {
+		return true
+	}
+	return c.referenceGrantAllows(routeNamespace, string(*ref.Namespace), string(ref.Name))
+}
+
+// referenceGrantAllows reports whether some ReferenceGrant in toNamespace
+// authorizes an HTTPRoute in fromNamespace to reference the named Service.
+func (c *Controller) referenceGrantAllows(fromNamespace, toNamespace, serviceName string) bool {
+	for _, obj := range c.refGrantInformer.GetStore().List() {
+		grant, ok := obj.(*gatewayapi.ReferenceGrant)
+		if !ok || grant.Namespace != toNamespace {
+			continue
+		}
+
+		fromAllowed := false
+		for _, from := range grant.Spec.From {
+			if string(from.Group) == gatewayGroup && string(from.Kind) == httpRouteGroupKind && string(from.Namespace) == fromNamespace {
+				fromAllowed = true
+				break
+			}
+		}
+		if !fromAllowed {
+			continue
+		}
+
+		for _, to := range grant.Spec.To {
+			if string(to.Group) != coreGroup || string(to.Kind) != serviceKind {
+				continue
+			}
+			if to.Name == nil || string(*to.Name) == serviceName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package gateway
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	gatewayapi "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/solo-io/gloo-ingress-controller/configaggregate"
+	"github.com/solo-io/gloo-storage"
+	gloov1 "github.com/solo-io/gloo/pkg/api/types/v1"
+)
+
+// translateRoute converts a single owned HTTPRoute into a Gloo
+// VirtualService (one route per rule/match, routed to the rule's first
+// backendRef) and upserts it into c.store, namespacing the generated
+// object name via configaggregate so it can't collide with one produced
+// by the ingress translator.
+//
+// Like ingress.translate, this only covers host/path routing to a single
+// backend Service; weighting across multiple backendRefs and filters are
+// not yet translated. A rule whose backendRef crosses namespaces is
+// skipped unless c.referenceGrantAllows confirms a ReferenceGrant in the
+// backend's namespace authorizes it, per the Gateway API spec.
+func (c *Controller) translateRoute(route *gatewayapi.HTTPRoute) error {
+	name := configaggregate.Name(configaggregate.SourceGateway, route.Namespace, route.Name)
+
+	vs := &gloov1.VirtualService{Name: name}
+	for _, hostname := range route.Spec.Hostnames {
+		vs.Domains = append(vs.Domains, string(hostname))
+	}
+
+	for _, rule := range route.Spec.Rules {
+		if len(rule.BackendRefs) == 0 {
+			continue
+		}
+		backend := rule.BackendRefs[0]
+		if !c.backendRefAllowed(route.Namespace, backend) {
+			continue
+		}
+		upstream := routeUpstreamName(route.Namespace, backend)
+
+		if len(rule.Matches) == 0 {
+			vs.Routes = append(vs.Routes, &gloov1.Route{UpstreamName: upstream})
+			continue
+		}
+		for _, match := range rule.Matches {
+			pathPrefix := "/"
+			if match.Path != nil && match.Path.Value != nil {
+				pathPrefix = *match.Path.Value
+			}
+			vs.Routes = append(vs.Routes, &gloov1.Route{
+				PathPrefix:   pathPrefix,
+				UpstreamName: upstream,
+			})
+		}
+	}
+
+	return upsertVirtualService(c.store, vs)
+}
+
+// routeUpstreamName is the Gloo Upstream name gloo-k8s-discovery generates
+// for the Service a backendRef points at, defaulting the ref's namespace
+// to the HTTPRoute's own per the Gateway API spec.
+func routeUpstreamName(routeNamespace string, ref gatewayapi.HTTPBackendRef) string {
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	port := int32(0)
+	if ref.Port != nil {
+		port = int32(*ref.Port)
+	}
+	return fmt.Sprintf("%s-%s-%d", namespace, ref.Name, port)
+}
+
+// upsertVirtualService creates vs, or updates the existing object with the
+// same name, making translateRoute's writes idempotent across repeated
+// syncs.
+func upsertVirtualService(store storage.Interface, vs *gloov1.VirtualService) error {
+	existing, err := store.V1().VirtualServices().Get(vs.Name)
+	if err != nil {
+		if _, err := store.V1().VirtualServices().Create(vs); err != nil {
+			return errors.Wrapf(err, "creating virtual service %v", vs.Name)
+		}
+		return nil
+	}
+
+	vs.Metadata = existing.Metadata
+	if _, err := store.V1().VirtualServices().Update(vs); err != nil {
+		return errors.Wrapf(err, "updating virtual service %v", vs.Name)
+	}
+	return nil
+}
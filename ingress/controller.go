@@ -0,0 +1,275 @@
+package ingress
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/solo-io/gloo-ingress-controller/cloudprovider"
+	"github.com/solo-io/gloo-storage"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// DefaultIngressClassController is the controller string an IngressClass
+// must reference in spec.controller for this controller to claim it.
+const DefaultIngressClassController = "gloo.solo.io/ingress-controller"
+
+const legacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// apiVersion identifies which Ingress API group/version this controller
+// negotiated with the apiserver.
+type apiVersion int
+
+const (
+	apiVersionExtensionsV1beta1 apiVersion = iota
+	apiVersionNetworkingV1
+)
+
+// IngressController watches Ingress resources (and, when the cluster serves
+// networking.k8s.io/v1, IngressClass resources) and translates the ones it
+// owns into Gloo config via store.
+type IngressController struct {
+	store storage.Interface
+
+	version apiVersion
+
+	useAsGlobalIngress bool
+	ingressClassName   string
+	controllerName     string
+
+	cloud cloudprovider.LBProvisioner
+
+	ingressInformer cache.SharedIndexInformer
+	classInformer   cache.SharedIndexInformer
+
+	errs chan error
+}
+
+// NewIngressController creates an IngressController.
+//
+// The controller negotiates, via discovery, whether to watch the legacy
+// extensions/v1beta1 Ingress or networking.k8s.io/v1 Ingress (and, for the
+// latter, IngressClass). ingressClassName restricts translation to
+// Ingresses whose spec.ingressClassName or kubernetes.io/ingress.class
+// annotation matches; when empty, useAsGlobalIngress decides whether
+// un-annotated Ingresses are claimed. cloud may be nil; when set, owned
+// Ingresses also get a cloud load balancer provisioned for them.
+func NewIngressController(cfg *rest.Config, store storage.Interface, syncFrequency time.Duration, useAsGlobalIngress bool, ingressClassName string, cloud cloudprovider.LBProvisioner) (*IngressController, error) {
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube clientset")
+	}
+
+	version, err := negotiateIngressVersion(kube.Discovery())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to negotiate ingress api version with apiserver")
+	}
+
+	c := &IngressController{
+		store:              store,
+		version:            version,
+		useAsGlobalIngress: useAsGlobalIngress,
+		ingressClassName:   ingressClassName,
+		controllerName:     DefaultIngressClassController,
+		cloud:              cloud,
+		errs:               make(chan error),
+	}
+
+	switch version {
+	case apiVersionNetworkingV1:
+		log.Printf("networking.k8s.io/v1 ingress api detected, watching Ingress and IngressClass")
+		c.ingressInformer = cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+					return kube.NetworkingV1().Ingresses(metav1.NamespaceAll).List(options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+					return kube.NetworkingV1().Ingresses(metav1.NamespaceAll).Watch(options)
+				},
+			},
+			&networkingv1.Ingress{}, syncFrequency, cache.Indexers{},
+		)
+		c.classInformer = cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+					return kube.NetworkingV1().IngressClasses().List(options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+					return kube.NetworkingV1().IngressClasses().Watch(options)
+				},
+			},
+			&networkingv1.IngressClass{}, syncFrequency, cache.Indexers{},
+		)
+	default:
+		log.Printf("networking.k8s.io/v1 not available, falling back to extensions/v1beta1 ingress api")
+		c.ingressInformer = cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (interface{}, error) {
+					return kube.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).List(options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (interface{}, error) {
+					return kube.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).Watch(options)
+				},
+			},
+			&extensionsv1beta1.Ingress{}, syncFrequency, cache.Indexers{},
+		)
+	}
+
+	c.ingressInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.sync() },
+		UpdateFunc: func(old, new interface{}) { c.sync() },
+		DeleteFunc: func(obj interface{}) { c.sync() },
+	})
+
+	return c, nil
+}
+
+// negotiateIngressVersion mirrors the version-negotiation Istio's pilot
+// config controller performs between ingress and ingressv1: prefer
+// networking.k8s.io/v1 when the apiserver serves it, and fall back to the
+// legacy extensions/v1beta1 Ingress only when the apiserver genuinely
+// doesn't serve that group/version. Any other discovery error (a
+// transient connection failure, a partial discovery aggregate error, ...)
+// is returned rather than silently treated as "not found" — a controller
+// that guessed wrong here would run with class filtering permanently
+// disabled for its entire lifetime.
+func negotiateIngressVersion(disc discovery.DiscoveryInterface) (apiVersion, error) {
+	resources, err := disc.ServerResourcesForGroupVersion("networking.k8s.io/v1")
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apiVersionExtensionsV1beta1, nil
+		}
+		return apiVersionExtensionsV1beta1, errors.Wrap(err, "checking apiserver support for networking.k8s.io/v1")
+	}
+	for _, r := range resources.APIResources {
+		if r.Kind == "Ingress" {
+			return apiVersionNetworkingV1, nil
+		}
+	}
+	return apiVersionExtensionsV1beta1, nil
+}
+
+// Run starts the informers and blocks until stop is closed.
+func (c *IngressController) Run(stop <-chan struct{}) {
+	go c.ingressInformer.Run(stop)
+	if c.classInformer != nil {
+		go c.classInformer.Run(stop)
+	}
+	<-stop
+}
+
+// Error returns a channel of errors encountered while translating Ingress
+// objects into Gloo config.
+func (c *IngressController) Error() <-chan error {
+	return c.errs
+}
+
+// Owns reports whether c would translate the given Ingress object into
+// Gloo config. IngressSyncer delegates to this (rather than keeping its
+// own copy of the ownership rules) so the set of Ingresses whose status
+// gets updated can never drift from the set actually translated.
+func (c *IngressController) Owns(obj interface{}) bool {
+	return c.owns(obj)
+}
+
+// Version reports which Ingress API c negotiated with the apiserver, so
+// IngressSyncer can list against that same API.
+func (c *IngressController) Version() apiVersion {
+	return c.version
+}
+
+// sync re-translates every Ingress this controller owns into Gloo config.
+// Errors are reported on c.errs rather than returned, matching the
+// fire-and-forget event handlers registered against the informer.
+func (c *IngressController) sync() {
+	for _, obj := range c.ingressInformer.GetStore().List() {
+		if !c.owns(obj) {
+			continue
+		}
+		if err := c.translate(obj); err != nil {
+			c.errs <- errors.Wrap(err, "translating ingress to gloo config")
+		}
+	}
+}
+
+// owns reports whether this controller should manage the given Ingress.
+// When --ingress-class is set, an exact spec.ingressClassName / legacy
+// annotation match is both necessary and sufficient: the operator has
+// already disambiguated which controller that class name belongs to.
+// When it's unset, an un-classed Ingress is claimed only if
+// useAsGlobalIngress; a classed one is claimed only if its IngressClass
+// (networking.k8s.io/v1) can be confirmed to point at this controller, so
+// a bare "any annotation" never lets this controller steal an Ingress
+// meant for another one (nginx, traefik, ...) sharing the cluster.
+func (c *IngressController) owns(obj interface{}) bool {
+	class, hasClass := ingressClassOf(obj)
+
+	if c.ingressClassName != "" {
+		return hasClass && class == c.ingressClassName
+	}
+
+	if hasClass {
+		return c.classControllerMatches(class)
+	}
+
+	return c.useAsGlobalIngress
+}
+
+// classControllerMatches returns true only when className's IngressClass
+// has actually been observed and confirmed to reference this controller's
+// controllerName. It cannot be verified at all against extensions/v1beta1
+// (no IngressClass informer exists there) or when the named IngressClass
+// hasn't been observed, so both cases return false rather than defaulting
+// to ownership.
+func (c *IngressController) classControllerMatches(className string) bool {
+	if c.classInformer == nil {
+		return false
+	}
+	obj, exists, err := c.classInformer.GetStore().GetByKey(className)
+	if err != nil || !exists {
+		return false
+	}
+	class, ok := obj.(*networkingv1.IngressClass)
+	if !ok {
+		return false
+	}
+	return class.Spec.Controller == c.controllerName
+}
+
+// ingressClassOf extracts the effective ingress class for obj, preferring
+// spec.ingressClassName and falling back to the legacy
+// kubernetes.io/ingress.class annotation.
+func ingressClassOf(obj interface{}) (class string, ok bool) {
+	switch ing := obj.(type) {
+	case *networkingv1.Ingress:
+		if ing.Spec.IngressClassName != nil && *ing.Spec.IngressClassName != "" {
+			return *ing.Spec.IngressClassName, true
+		}
+		if v, ok := ing.Annotations[legacyIngressClassAnnotation]; ok {
+			return v, true
+		}
+	case *extensionsv1beta1.Ingress:
+		if v, ok := ing.Annotations[legacyIngressClassAnnotation]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func metaOf(obj interface{}) (namespace, name string) {
+	switch ing := obj.(type) {
+	case *networkingv1.Ingress:
+		return ing.Namespace, ing.Name
+	case *extensionsv1beta1.Ingress:
+		return ing.Namespace, ing.Name
+	}
+	return "", ""
+}
@@ -0,0 +1,158 @@
+package ingress
+
+import (
+	"testing"
+
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func ingressClassName(name string) *string { return &name }
+
+// classInformerWithClasses builds a SharedIndexInformer whose store already
+// contains classes, without running it, so classControllerMatches has
+// something to look up.
+func classInformerWithClasses(classes ...*networkingv1.IngressClass) cache.SharedIndexInformer {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc:  func(options metav1.ListOptions) (interface{}, error) { return &networkingv1.IngressClassList{}, nil },
+			WatchFunc: func(options metav1.ListOptions) (interface{}, error) { return nil, nil },
+		},
+		&networkingv1.IngressClass{}, 0, cache.Indexers{},
+	)
+	for _, class := range classes {
+		informer.GetStore().Add(class)
+	}
+	return informer
+}
+
+func TestIngressControllerOwns(t *testing.T) {
+	cases := []struct {
+		name string
+		ctl  *IngressController
+		obj  interface{}
+		want bool
+	}{
+		{
+			name: "global ingress claims unannotated ingress",
+			ctl:  &IngressController{useAsGlobalIngress: true},
+			obj:  &extensionsv1beta1.Ingress{},
+			want: true,
+		},
+		{
+			name: "not global ingress skips unannotated ingress",
+			ctl:  &IngressController{useAsGlobalIngress: false},
+			obj:  &extensionsv1beta1.Ingress{},
+			want: false,
+		},
+		{
+			name: "legacy annotation matches configured class",
+			ctl:  &IngressController{ingressClassName: "gloo"},
+			obj: &extensionsv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{legacyIngressClassAnnotation: "gloo"}},
+			},
+			want: true,
+		},
+		{
+			name: "legacy annotation mismatched class",
+			ctl:  &IngressController{ingressClassName: "gloo"},
+			obj: &extensionsv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{legacyIngressClassAnnotation: "nginx"}},
+			},
+			want: false,
+		},
+		{
+			name: "classed ingress with no ingress-class flag and no class informer is never claimed",
+			ctl:  &IngressController{useAsGlobalIngress: true},
+			obj: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{IngressClassName: ingressClassName("nginx")},
+			},
+			want: false,
+		},
+		{
+			name: "configured class required but ingress has none",
+			ctl:  &IngressController{ingressClassName: "gloo"},
+			obj:  &networkingv1.Ingress{},
+			want: false,
+		},
+		{
+			name: "no ingress-class flag, class's IngressClass confirms this controller",
+			ctl: &IngressController{
+				controllerName: DefaultIngressClassController,
+				classInformer: classInformerWithClasses(&networkingv1.IngressClass{
+					ObjectMeta: metav1.ObjectMeta{Name: "gloo"},
+					Spec:       networkingv1.IngressClassSpec{Controller: DefaultIngressClassController},
+				}),
+			},
+			obj: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{IngressClassName: ingressClassName("gloo")},
+			},
+			want: true,
+		},
+		{
+			name: "no ingress-class flag, class's IngressClass points at a different controller",
+			ctl: &IngressController{
+				controllerName: DefaultIngressClassController,
+				classInformer: classInformerWithClasses(&networkingv1.IngressClass{
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+					Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+				}),
+			},
+			obj: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{IngressClassName: ingressClassName("nginx")},
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.ctl.owns(tc.obj); got != tc.want {
+				t.Errorf("owns() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIngressClassOf(t *testing.T) {
+	cases := []struct {
+		name   string
+		obj    interface{}
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "networking v1 spec class wins over annotation",
+			obj:    &networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: ingressClassName("gloo")}, ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{legacyIngressClassAnnotation: "nginx"}}},
+			want:   "gloo",
+			wantOK: true,
+		},
+		{
+			name:   "networking v1 falls back to legacy annotation",
+			obj:    &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{legacyIngressClassAnnotation: "nginx"}}},
+			want:   "nginx",
+			wantOK: true,
+		},
+		{
+			name:   "extensions v1beta1 only has the legacy annotation",
+			obj:    &extensionsv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{legacyIngressClassAnnotation: "nginx"}}},
+			want:   "nginx",
+			wantOK: true,
+		},
+		{
+			name: "no class set anywhere",
+			obj:  &networkingv1.Ingress{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := ingressClassOf(tc.obj)
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("ingressClassOf() = (%q, %v), want (%q, %v)", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
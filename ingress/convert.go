@@ -0,0 +1,24 @@
+package ingress
+
+import (
+	"github.com/pkg/errors"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// toNetworkingV1 adapts a legacy extensions/v1beta1 Ingress (or an
+// already-networking.k8s.io/v1 one) to *networkingv1.Ingress, the type
+// cloudprovider.LBProvisioner is defined in terms of. Only the identifying
+// metadata is copied: provisioners key cloud resources off
+// namespace/name/class, not the full rule set.
+func toNetworkingV1(obj interface{}) (*networkingv1.Ingress, error) {
+	switch ing := obj.(type) {
+	case *networkingv1.Ingress:
+		return ing, nil
+	case *extensionsv1beta1.Ingress:
+		return &networkingv1.Ingress{
+			ObjectMeta: ing.ObjectMeta,
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported ingress type %T", obj)
+}
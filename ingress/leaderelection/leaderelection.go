@@ -0,0 +1,150 @@
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// Options configures the leader election lock used to coordinate multiple
+// replicas of the ingress controller.
+type Options struct {
+	// LockName is the name of the configmap/lease used as the resource lock.
+	LockName string
+
+	// Namespace is the namespace the lock object lives in.
+	Namespace string
+
+	// Identity uniquely identifies this replica's leader election candidacy.
+	// Defaults to the pod hostname if unset.
+	Identity string
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// Run blocks, participating in leader election using opts, until stop is
+// closed. Each time this replica wins the lease, onStartedLeading is
+// called with a fresh stop channel that is closed the moment leadership is
+// lost or relinquished; onStoppedLeading is then called once that happens.
+// Losing the lease (a missed renewal, an API server blip, ...) does not
+// stop this replica from competing for it again — client-go's
+// LeaderElector.Run returns as soon as leadership is lost, so Run loops,
+// re-entering the race, until stop is closed.
+//
+// When stop is closed, Run cancels the elector's context with
+// ReleaseOnCancel set, so a held lease is released immediately rather than
+// left to expire, and only returns once the elector has actually stopped.
+func Run(cfg *rest.Config, opts Options, stop <-chan struct{}, onStartedLeading func(stop <-chan struct{}), onStoppedLeading func()) error {
+	identity := opts.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "failed to determine hostname for leader election identity")
+		}
+		identity = hostname
+	}
+
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create kube client for leader election")
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.ConfigMapsLeasesResourceLock,
+		opts.Namespace,
+		opts.LockName,
+		kube.CoreV1(),
+		kube.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create leader election resource lock")
+	}
+
+	// leaderStop is set by OnStartedLeading and cleared by OnStoppedLeading.
+	// client-go calls OnStoppedLeading unconditionally on every Run cycle —
+	// including ones where this replica never won the lease at all, and
+	// from a different goroutine than the one OnStartedLeading runs in —
+	// so both the nil case (never started leading) and the already-closed
+	// case (a prior cycle's OnStoppedLeading already ran) have to be
+	// guarded against here, under a mutex.
+	var mu sync.Mutex
+	var leaderStop chan struct{}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   opts.LeaseDuration,
+		RenewDeadline:   opts.RenewDeadline,
+		RetryPeriod:     opts.RetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Printf("%v: acquired leader lease %v/%v", identity, opts.Namespace, opts.LockName)
+				mu.Lock()
+				leaderStop = make(chan struct{})
+				startedStop := leaderStop
+				mu.Unlock()
+				onStartedLeading(startedStop)
+			},
+			OnStoppedLeading: func() {
+				log.Printf("%v: lost leader lease %v/%v", identity, opts.Namespace, opts.LockName)
+				mu.Lock()
+				stoppedStop := leaderStop
+				leaderStop = nil
+				mu.Unlock()
+				if stoppedStop != nil {
+					close(stoppedStop)
+				}
+				if onStoppedLeading != nil {
+					onStoppedLeading()
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create leader elector")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	for {
+		elector.Run(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			// Lost or failed to acquire the lease; re-enter the race.
+		}
+	}
+}
+
+// ValidateOptions returns an error if the provided durations would produce
+// an elector that client-go will refuse to construct.
+func ValidateOptions(opts Options) error {
+	if opts.LeaseDuration <= opts.RenewDeadline {
+		return fmt.Errorf("lease-duration (%v) must be greater than renew-deadline (%v)", opts.LeaseDuration, opts.RenewDeadline)
+	}
+	if opts.RetryPeriod <= 0 {
+		return fmt.Errorf("retry-period (%v) must be greater than zero", opts.RetryPeriod)
+	}
+	if opts.RenewDeadline <= time.Duration(leaderelection.JitterFactor*float64(opts.RetryPeriod)) {
+		return fmt.Errorf("renew-deadline (%v) must be greater than retry-period (%v) * %v", opts.RenewDeadline, opts.RetryPeriod, leaderelection.JitterFactor)
+	}
+	return nil
+}
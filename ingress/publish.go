@@ -0,0 +1,179 @@
+package ingress
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// publishSource identifies where an IngressSyncer sources the addresses it
+// writes into Ingress status.loadBalancer.
+type publishSource int
+
+const (
+	// publishSourceNodeIP is the default: the syncer reads its own pod's
+	// node and reports that node's address, for NodePort/hostNetwork
+	// deployments with no LB service in front of them.
+	publishSourceNodeIP publishSource = iota
+	// publishSourceService reports the load balancer ingress points of a
+	// named Service, the traditional in-cluster LB-proxy deployment.
+	publishSourceService
+	// publishSourceStatic reports a fixed, operator-provided list of
+	// addresses, for externally managed LBs.
+	publishSourceStatic
+)
+
+// PublishOptions configures where an IngressSyncer sources the addresses
+// it reconciles into Ingress status.loadBalancer.ingress. At most one of
+// PublishService or PublishAddresses may be set; if neither is set, the
+// syncer falls back to the node IP of the pod it's running in.
+type PublishOptions struct {
+	// UpdateStatus gates whether Ingress status is written at all.
+	UpdateStatus bool
+
+	// PublishService is a "namespace/name" reference to the Service whose
+	// status.loadBalancer.ingress should be mirrored onto every Ingress.
+	PublishService string
+
+	// PublishAddresses is a fixed list of hostnames and/or IPs to publish,
+	// for use behind an externally managed load balancer.
+	PublishAddresses []string
+
+	// PodName and PodNamespace identify this syncer's own pod, via the
+	// downward API, for the node-IP fallback source.
+	PodName      string
+	PodNamespace string
+}
+
+// source resolves which publishSource these options select, validating
+// that at most one explicit source is configured.
+func (o PublishOptions) source() (publishSource, error) {
+	hasService := o.PublishService != ""
+	hasAddresses := len(o.PublishAddresses) > 0
+
+	if hasService && hasAddresses {
+		return 0, errors.New("only one of --publish-service or --publish-address may be set")
+	}
+	if hasService {
+		return publishSourceService, nil
+	}
+	if hasAddresses {
+		return publishSourceStatic, nil
+	}
+	return publishSourceNodeIP, nil
+}
+
+// Validate returns an error if o is not a usable configuration. It's a
+// startup-time check: callers should refuse to run rather than silently
+// skip status updates on a typo'd flag.
+func (o PublishOptions) Validate() error {
+	if !o.UpdateStatus {
+		return nil
+	}
+	_, err := o.source()
+	if err != nil {
+		return err
+	}
+	if o.PublishService == "" && len(o.PublishAddresses) == 0 && (o.PodName == "" || o.PodNamespace == "") {
+		return errors.New("--update-status=true requires --publish-service, --publish-address, or the POD_NAME/POD_NAMESPACE downward API fields to be set for the node-ip fallback")
+	}
+	return nil
+}
+
+// ParsePublishAddresses splits a comma-separated --publish-address flag
+// value into its constituent hostnames/IPs, discarding empty entries.
+func ParsePublishAddresses(flag string) []string {
+	var out []string
+	for _, addr := range strings.Split(flag, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr != "" {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
+// resolveLoadBalancerIngress returns the addresses to publish, according to
+// whichever source opts selects.
+func (s *IngressSyncer) resolveLoadBalancerIngress() ([]corev1.LoadBalancerIngress, error) {
+	switch s.publishSource {
+	case publishSourceService:
+		ns, name, err := splitNamespacedName(s.publishOpts.PublishService)
+		if err != nil {
+			return nil, err
+		}
+		svc, err := s.kube.CoreV1().Services(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "looking up publish service %v/%v", ns, name)
+		}
+		return svc.Status.LoadBalancer.Ingress, nil
+
+	case publishSourceStatic:
+		return staticLoadBalancerIngress(s.publishOpts.PublishAddresses), nil
+
+	default: // publishSourceNodeIP
+		return s.nodeIPLoadBalancerIngress()
+	}
+}
+
+// staticLoadBalancerIngress converts operator-provided --publish-address
+// entries into LoadBalancerIngress values, treating parseable IPs as IPs
+// and everything else as a hostname.
+func staticLoadBalancerIngress(addresses []string) []corev1.LoadBalancerIngress {
+	var out []corev1.LoadBalancerIngress
+	for _, addr := range addresses {
+		if ip := net.ParseIP(addr); ip != nil {
+			out = append(out, corev1.LoadBalancerIngress{IP: addr})
+			continue
+		}
+		out = append(out, corev1.LoadBalancerIngress{Hostname: addr})
+	}
+	return out
+}
+
+// nodeIPLoadBalancerIngress looks up the node this syncer's own pod is
+// running on (via the POD_NAME/POD_NAMESPACE downward-API fields) and
+// reports that node's address, preferring an ExternalIP over an
+// InternalIP.
+func (s *IngressSyncer) nodeIPLoadBalancerIngress() ([]corev1.LoadBalancerIngress, error) {
+	pod, err := s.kube.CoreV1().Pods(s.publishOpts.PodNamespace).Get(s.publishOpts.PodName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up own pod %v/%v", s.publishOpts.PodNamespace, s.publishOpts.PodName)
+	}
+	node, err := s.kube.CoreV1().Nodes().Get(pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up node %v", pod.Spec.NodeName)
+	}
+
+	var internal string
+	for _, addr := range node.Status.Addresses {
+		switch addr.Type {
+		case corev1.NodeExternalIP:
+			return []corev1.LoadBalancerIngress{{IP: addr.Address}}, nil
+		case corev1.NodeInternalIP:
+			internal = addr.Address
+		}
+	}
+	if internal == "" {
+		return nil, errors.Errorf("node %v has no ExternalIP or InternalIP address", pod.Spec.NodeName)
+	}
+	return []corev1.LoadBalancerIngress{{IP: internal}}, nil
+}
+
+func splitNamespacedName(s string) (namespace, name string, err error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("--publish-service must be of the form <namespace>/<name>, got %q", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// PodEnvOptions returns the POD_NAME/POD_NAMESPACE downward-API environment
+// variables, for main to copy into PublishOptions.PodName/PodNamespace.
+func PodEnvOptions() (podName, podNamespace string) {
+	return os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+}
@@ -0,0 +1,136 @@
+package ingress
+
+import (
+	"testing"
+)
+
+func TestPublishOptionsSource(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    PublishOptions
+		want    publishSource
+		wantErr bool
+	}{
+		{
+			name: "neither set falls back to node ip",
+			opts: PublishOptions{},
+			want: publishSourceNodeIP,
+		},
+		{
+			name: "service set",
+			opts: PublishOptions{PublishService: "ns/svc"},
+			want: publishSourceService,
+		},
+		{
+			name: "addresses set",
+			opts: PublishOptions{PublishAddresses: []string{"1.2.3.4"}},
+			want: publishSourceStatic,
+		},
+		{
+			name:    "both set is an error",
+			opts:    PublishOptions{PublishService: "ns/svc", PublishAddresses: []string{"1.2.3.4"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.opts.source()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("source() = %v, nil; want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("source() error = %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("source() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPublishOptionsValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    PublishOptions
+		wantErr bool
+	}{
+		{
+			name: "update status disabled never errors",
+			opts: PublishOptions{UpdateStatus: false},
+		},
+		{
+			name:    "update status enabled with no source and no pod env",
+			opts:    PublishOptions{UpdateStatus: true},
+			wantErr: true,
+		},
+		{
+			name: "update status enabled with publish service",
+			opts: PublishOptions{UpdateStatus: true, PublishService: "ns/svc"},
+		},
+		{
+			name: "update status enabled with pod env fallback",
+			opts: PublishOptions{UpdateStatus: true, PodName: "pod", PodNamespace: "ns"},
+		},
+		{
+			name:    "conflicting sources",
+			opts:    PublishOptions{UpdateStatus: true, PublishService: "ns/svc", PublishAddresses: []string{"1.2.3.4"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestStaticLoadBalancerIngress(t *testing.T) {
+	out := staticLoadBalancerIngress([]string{"1.2.3.4", "lb.example.com", "::1"})
+	if len(out) != 3 {
+		t.Fatalf("len(out) = %v, want 3", len(out))
+	}
+	if out[0].IP != "1.2.3.4" || out[0].Hostname != "" {
+		t.Errorf("out[0] = %+v, want IP-only 1.2.3.4", out[0])
+	}
+	if out[1].Hostname != "lb.example.com" || out[1].IP != "" {
+		t.Errorf("out[1] = %+v, want hostname-only lb.example.com", out[1])
+	}
+	if out[2].IP != "::1" {
+		t.Errorf("out[2] = %+v, want IP-only ::1", out[2])
+	}
+}
+
+func TestSplitNamespacedName(t *testing.T) {
+	cases := []struct {
+		in       string
+		ns, name string
+		wantErr  bool
+	}{
+		{in: "ns/svc", ns: "ns", name: "svc"},
+		{in: "svc", wantErr: true},
+		{in: "/svc", wantErr: true},
+		{in: "ns/", wantErr: true},
+		{in: "ns/svc/extra", ns: "ns", name: "svc/extra"},
+	}
+
+	for _, tc := range cases {
+		ns, name, err := splitNamespacedName(tc.in)
+		if tc.wantErr != (err != nil) {
+			t.Fatalf("splitNamespacedName(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if ns != tc.ns || name != tc.name {
+			t.Errorf("splitNamespacedName(%q) = (%q, %q), want (%q, %q)", tc.in, ns, name, tc.ns, tc.name)
+		}
+	}
+}
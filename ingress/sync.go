@@ -0,0 +1,187 @@
+package ingress
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/solo-io/gloo-ingress-controller/cloudprovider"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// IngressSyncer reconciles every owned Ingress's status.loadBalancer with
+// the addresses of a cloud LBProvisioner when one is configured, and
+// otherwise with those of whichever source publishOpts selects: a named
+// Service, a static address list, or this pod's own node IP.
+//
+// Ownership and API-version selection are delegated to ctl rather than
+// kept as a second copy here, so the set of Ingresses whose status this
+// syncer updates can never drift from the set ctl actually translates.
+type IngressSyncer struct {
+	kube kubernetes.Interface
+	ctl  *IngressController
+
+	publishOpts   PublishOptions
+	publishSource publishSource
+
+	cloud cloudprovider.LBProvisioner
+
+	errs chan error
+}
+
+// NewIngressSyncer creates an IngressSyncer that periodically reconciles
+// Ingress status.loadBalancer from publishOpts, stopping when stop is
+// closed. It returns an error if publishOpts is not a valid configuration.
+// cloud may be nil, in which case publishOpts alone determines the
+// published address. ctl's ownership rules and negotiated Ingress API
+// version are reused as-is.
+func NewIngressSyncer(cfg *rest.Config, syncFrequency time.Duration, stop <-chan struct{}, ctl *IngressController, publishOpts PublishOptions, cloud cloudprovider.LBProvisioner) (*IngressSyncer, error) {
+	if err := publishOpts.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid publish options")
+	}
+
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube clientset")
+	}
+
+	source, err := publishOpts.source()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &IngressSyncer{
+		kube:          kube,
+		ctl:           ctl,
+		publishOpts:   publishOpts,
+		publishSource: source,
+		cloud:         cloud,
+		errs:          make(chan error),
+	}
+
+	go s.run(syncFrequency, stop)
+
+	return s, nil
+}
+
+// Error returns a channel of errors encountered while syncing ingress
+// status.
+func (s *IngressSyncer) Error() <-chan error {
+	return s.errs
+}
+
+func (s *IngressSyncer) run(syncFrequency time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(syncFrequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.syncOnce(); err != nil {
+				s.errs <- errors.Wrap(err, "syncing ingress status")
+			}
+		case <-stop:
+			log.Printf("stopping ingress status sync")
+			return
+		}
+	}
+}
+
+func (s *IngressSyncer) syncOnce() error {
+	if !s.publishOpts.UpdateStatus {
+		return nil
+	}
+
+	var fallback []corev1.LoadBalancerIngress
+	if s.cloud == nil {
+		var err error
+		fallback, err = s.resolveLoadBalancerIngress()
+		if err != nil {
+			return errors.Wrap(err, "resolving publish address")
+		}
+	}
+
+	var active []string
+
+	switch s.ctl.Version() {
+	case apiVersionNetworkingV1:
+		ingresses, err := s.kube.NetworkingV1().Ingresses(metav1.NamespaceAll).List(metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "listing ingresses")
+		}
+		for i := range ingresses.Items {
+			ing := &ingresses.Items[i]
+			if !s.ctl.Owns(ing) {
+				continue
+			}
+			active = append(active, ing.Namespace+"/"+ing.Name)
+			lbIngress, err := s.loadBalancerIngress(fallback, ing)
+			if err != nil {
+				return errors.Wrapf(err, "provisioning cloud load balancer for ingress %v/%v", ing.Namespace, ing.Name)
+			}
+			ing.Status.LoadBalancer.Ingress = lbIngress
+			if _, err := s.kube.NetworkingV1().Ingresses(ing.Namespace).UpdateStatus(ing); err != nil {
+				return errors.Wrapf(err, "updating status for ingress %v/%v", ing.Namespace, ing.Name)
+			}
+		}
+	default:
+		ingresses, err := s.kube.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).List(metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "listing ingresses")
+		}
+		for i := range ingresses.Items {
+			ing := &ingresses.Items[i]
+			if !s.ctl.Owns(ing) {
+				continue
+			}
+			active = append(active, ing.Namespace+"/"+ing.Name)
+			lbIngress, err := s.loadBalancerIngress(fallback, ing)
+			if err != nil {
+				return errors.Wrapf(err, "provisioning cloud load balancer for ingress %v/%v", ing.Namespace, ing.Name)
+			}
+			ing.Status.LoadBalancer.Ingress = lbIngress
+			if _, err := s.kube.ExtensionsV1beta1().Ingresses(ing.Namespace).UpdateStatus(ing); err != nil {
+				return errors.Wrapf(err, "updating status for ingress %v/%v", ing.Namespace, ing.Name)
+			}
+		}
+	}
+
+	if s.cloud != nil {
+		if err := s.cloud.GC(active); err != nil {
+			return errors.Wrap(err, "garbage collecting cloud load balancer resources")
+		}
+	}
+
+	return nil
+}
+
+// loadBalancerIngress returns the addresses to publish on ing's status:
+// fallback, unless a cloud provider is configured, in which case it
+// provisions (or looks up) that Ingress's cloud load balancer instead.
+func (s *IngressSyncer) loadBalancerIngress(fallback []corev1.LoadBalancerIngress, ing interface{}) ([]corev1.LoadBalancerIngress, error) {
+	if s.cloud == nil {
+		return fallback, nil
+	}
+	return s.cloudLoadBalancerIngress(ing)
+}
+
+// cloudLoadBalancerIngress asks s.cloud to ensure a load balancer exists
+// for ing and converts its result to the corev1 status shape.
+func (s *IngressSyncer) cloudLoadBalancerIngress(ing interface{}) ([]corev1.LoadBalancerIngress, error) {
+	netIng, err := toNetworkingV1(ing)
+	if err != nil {
+		return nil, err
+	}
+	lbs, err := s.cloud.EnsureLB(netIng)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]corev1.LoadBalancerIngress, 0, len(lbs))
+	for _, lb := range lbs {
+		out = append(out, corev1.LoadBalancerIngress{IP: lb.IP, Hostname: lb.Hostname})
+	}
+	return out, nil
+}
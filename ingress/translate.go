@@ -0,0 +1,121 @@
+package ingress
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/solo-io/gloo-ingress-controller/configaggregate"
+	"github.com/solo-io/gloo-storage"
+	gloov1 "github.com/solo-io/gloo/pkg/api/types/v1"
+	"github.com/solo-io/gloo/pkg/log"
+)
+
+// translate converts a single owned Ingress into a Gloo VirtualService
+// (one route per host+path rule, routed to the backend Service) and
+// upserts it into c.store, namespacing the generated object name via
+// configaggregate so it can't collide with one produced by the gateway-api
+// translator.
+//
+// This only covers host/path routing to a single backend Service per
+// rule, the same scope haproxy-ingress and ingress-gce's "default" path
+// handle; it does not yet translate TLS, rewrite, or annotation-driven
+// behavior.
+func (c *IngressController) translate(obj interface{}) error {
+	namespace, name := metaOf(obj)
+	vsName := configaggregate.Name(configaggregate.SourceIngress, namespace, name)
+
+	if c.cloud != nil {
+		if netIng, ok := obj.(*networkingv1.Ingress); ok {
+			if _, err := c.cloud.EnsureLB(netIng); err != nil {
+				// Cloud LB provisioning is additive to, not a precondition
+				// for, writing Gloo config: a provider that fails (or isn't
+				// implemented yet, see cloudprovider) must not stop every
+				// owned Ingress from being translated.
+				log.Printf("provisioning cloud load balancer for ingress %v/%v: %v", namespace, name, err)
+			}
+		}
+	}
+
+	vs, err := ingressToVirtualService(vsName, obj)
+	if err != nil {
+		return errors.Wrap(err, "translating ingress rules to a gloo virtual service")
+	}
+
+	return upsertVirtualService(c.store, vs)
+}
+
+// ingressToVirtualService builds the VirtualService for obj, dispatching
+// on its concrete Ingress type since extensions/v1beta1 and
+// networking.k8s.io/v1 use distinct (if structurally similar) rule types.
+func ingressToVirtualService(vsName string, obj interface{}) (*gloov1.VirtualService, error) {
+	switch ing := obj.(type) {
+	case *networkingv1.Ingress:
+		vs := &gloov1.VirtualService{Name: vsName}
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != "" {
+				vs.Domains = append(vs.Domains, rule.Host)
+			}
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil {
+					continue
+				}
+				vs.Routes = append(vs.Routes, &gloov1.Route{
+					PathPrefix:   path.Path,
+					UpstreamName: upstreamName(ing.Namespace, path.Backend.Service.Name, path.Backend.Service.Port.Number),
+				})
+			}
+		}
+		return vs, nil
+
+	case *extensionsv1beta1.Ingress:
+		vs := &gloov1.VirtualService{Name: vsName}
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != "" {
+				vs.Domains = append(vs.Domains, rule.Host)
+			}
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				vs.Routes = append(vs.Routes, &gloov1.Route{
+					PathPrefix:   path.Path,
+					UpstreamName: upstreamName(ing.Namespace, path.Backend.ServiceName, path.Backend.ServicePort.IntVal),
+				})
+			}
+		}
+		return vs, nil
+	}
+
+	return nil, errors.Errorf("unsupported ingress type %T", obj)
+}
+
+// upstreamName is the Gloo Upstream name gloo-k8s-discovery generates for a
+// Kubernetes Service port, so routes reference an Upstream the discovery
+// service will actually create.
+func upstreamName(namespace, service string, port int32) string {
+	return fmt.Sprintf("%s-%s-%d", namespace, service, port)
+}
+
+// upsertVirtualService creates vs, or updates the existing object with the
+// same name, making translate's writes idempotent across repeated syncs.
+func upsertVirtualService(store storage.Interface, vs *gloov1.VirtualService) error {
+	existing, err := store.V1().VirtualServices().Get(vs.Name)
+	if err != nil {
+		if _, err := store.V1().VirtualServices().Create(vs); err != nil {
+			return errors.Wrapf(err, "creating virtual service %v", vs.Name)
+		}
+		return nil
+	}
+
+	vs.Metadata = existing.Metadata
+	if _, err := store.V1().VirtualServices().Update(vs); err != nil {
+		return errors.Wrapf(err, "updating virtual service %v", vs.Name)
+	}
+	return nil
+}
@@ -8,10 +8,14 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/solo-io/gloo-ingress-controller/cloudprovider"
+	"github.com/solo-io/gloo-ingress-controller/gateway"
 	"github.com/solo-io/gloo-ingress-controller/ingress"
+	"github.com/solo-io/gloo-ingress-controller/ingress/leaderelection"
 	"github.com/solo-io/gloo-storage"
 	"github.com/solo-io/gloo-storage/crd"
 	"github.com/solo-io/gloo-storage/file"
@@ -30,7 +34,20 @@ func main() {
 var opts bootstrap.Options
 
 var globalIngress bool
-var ingressServiceName string
+var ingressClassName string
+var enableGatewayAPI bool
+
+var updateStatus bool
+var publishService string
+var publishAddress string
+
+var cloudProviderName string
+
+var fileWatchMode string
+var fileFormats string
+
+var leaderElect bool
+var leaderElectionOpts leaderelection.Options
 
 var rootCmd = &cobra.Command{
 	Use:   "gloo-ingress-controller",
@@ -46,7 +63,27 @@ var rootCmd = &cobra.Command{
 		}
 		stop := signals.SetupSignalHandler()
 
-		go runIngressController(cfg, store, stop)
+		if leaderElect {
+			leaderElectionOpts.Namespace = opts.KubeOptions.Namespace
+			if err := leaderelection.ValidateOptions(leaderElectionOpts); err != nil {
+				return errors.Wrap(err, "invalid leader election options")
+			}
+			go func() {
+				err := leaderelection.Run(cfg, leaderElectionOpts, stop,
+					func(leaderStop <-chan struct{}) {
+						runIngressController(cfg, store, leaderStop)
+					},
+					func() {
+						log.Printf("no longer the leader, standing by")
+					},
+				)
+				if err != nil {
+					log.Printf("leader election exited with error: %v", err)
+				}
+			}()
+		} else {
+			go runIngressController(cfg, store, stop)
+		}
 
 		<-stop
 		log.Printf("shutting down")
@@ -56,15 +93,33 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	// ingress-specific
-	rootCmd.PersistentFlags().BoolVar(&globalIngress, "global", true, "use gloo as the cluster-wide kubernetes ingress")
-	rootCmd.PersistentFlags().StringVar(&ingressServiceName, "service", "", "The name of the proxy service (envoy) if running in-cluster. If --service is set, the ingress controller will update ingress objects with the load balancer endpoints")
+	rootCmd.PersistentFlags().BoolVar(&globalIngress, "global", true, "use gloo as the cluster-wide kubernetes ingress for ingresses with no ingress class set. ignored if --ingress-class is set")
+	rootCmd.PersistentFlags().StringVar(&ingressClassName, "ingress-class", "", "only translate Ingresses whose spec.ingressClassName (or kubernetes.io/ingress.class annotation) matches this value, allowing multiple ingress controllers to coexist in one cluster")
+	rootCmd.PersistentFlags().BoolVar(&enableGatewayAPI, "enable-gateway-api", false, "also run a gateway.networking.k8s.io Gateway API controller alongside the ingress controller, translating Gateways/HTTPRoutes to gloo config")
+
+	// ingress status publishing
+	rootCmd.PersistentFlags().BoolVar(&updateStatus, "update-status", true, "update Ingress status.loadBalancer with the addresses from --publish-service, --publish-address, or (if neither is set) this pod's own node")
+	rootCmd.PersistentFlags().StringVar(&publishService, "publish-service", "", "the <namespace>/<name> of the proxy Service (e.g. envoy) whose load balancer address should be written to Ingress status. mutually exclusive with --publish-address")
+	rootCmd.PersistentFlags().StringVar(&publishAddress, "publish-address", "", "comma-separated list of hostnames/IPs to write to Ingress status, for use behind an externally managed load balancer. mutually exclusive with --publish-service")
+
+	// cloud provider
+	rootCmd.PersistentFlags().StringVar(&cloudProviderName, "cloud-provider", string(cloudprovider.None), "automatically provision a cloud load balancer per ingress. supported: [none | gce | aws | azure]")
 	rootCmd.PersistentFlags().DurationVar(&opts.ConfigWatcherOptions.SyncFrequency, "syncperiod", time.Minute*30, "sync period for watching ingress rules")
 
+	// leader election
+	rootCmd.PersistentFlags().BoolVar(&leaderElect, "leader-elect", false, "run multiple replicas of the ingress controller for HA, electing a single leader to write gloo config and update ingress status")
+	rootCmd.PersistentFlags().StringVar(&leaderElectionOpts.LockName, "leader-elect-lock-name", "gloo-ingress-controller-lock", "name of the configmap/lease used to coordinate leader election")
+	rootCmd.PersistentFlags().DurationVar(&leaderElectionOpts.LeaseDuration, "leader-elect-lease-duration", 15*time.Second, "duration non-leader candidates wait before forcing acquisition of the leader lease")
+	rootCmd.PersistentFlags().DurationVar(&leaderElectionOpts.RenewDeadline, "leader-elect-renew-deadline", 10*time.Second, "duration the leader retries refreshing its lease before giving it up")
+	rootCmd.PersistentFlags().DurationVar(&leaderElectionOpts.RetryPeriod, "leader-elect-retry-period", 2*time.Second, "duration leader election clients wait between action retries")
+
 	// config writer
 	rootCmd.PersistentFlags().StringVar(&opts.ConfigWatcherOptions.Type, "storage.type", bootstrap.WatcherTypeFile, fmt.Sprintf("storage backend for gloo config objects. supported: [%s]", strings.Join(bootstrap.SupportedCwTypes, " | ")))
 
 	// file
 	rootCmd.PersistentFlags().StringVar(&opts.FileOptions.ConfigDir, "file.config.dir", "_gloo_config", "root directory to use for storing gloo config files")
+	rootCmd.PersistentFlags().StringVar(&fileWatchMode, "file.watch-mode", fileWatchModePoll, "reserved for an upcoming inotify-backed watcher; the file config watcher currently always re-scans --file.config.dir every --syncperiod regardless of this value. accepted values: [poll | inotify], validated but otherwise unused today")
+	rootCmd.PersistentFlags().StringVar(&fileFormats, "file.formats", "yaml,json", "comma-separated list of file extensions accepted in --file.config.dir; files with any other extension are rejected at startup")
 
 	// kube
 	rootCmd.PersistentFlags().StringVar(&opts.KubeOptions.MasterURL, "master", "", "url of the kubernetes apiserver. not needed if running in-cluster")
@@ -79,6 +134,21 @@ func createStorageClient(opts bootstrap.Options) (storage.Interface, error) {
 		if dir == "" {
 			return nil, errors.New("must provide directory for file config watcher")
 		}
+		if err := validateFileWatchMode(fileWatchMode); err != nil {
+			return nil, err
+		}
+		formats, err := parseFileFormats(fileFormats)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateConfigDirFormats(dir, formats); err != nil {
+			return nil, errors.Wrap(err, "validating file config directory")
+		}
+		// fileWatchMode is validated above but not yet wired into the
+		// watcher itself: file.NewStorage only knows how to poll, so
+		// --file.watch-mode=inotify doesn't do anything beyond passing
+		// validation until a real fsnotify-backed watcher replaces this
+		// call.
 		client, err := file.NewStorage(dir, opts.ConfigWatcherOptions.SyncFrequency)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to start file config watcher for directory %v", dir)
@@ -98,30 +168,63 @@ func createStorageClient(opts bootstrap.Options) (storage.Interface, error) {
 	return nil, errors.Errorf("unknown or unspecified config watcher type: %v", opts.ConfigWatcherOptions.Type)
 }
 
+// newCloudProvisioner builds the LBProvisioner selected by --cloud-provider,
+// deriving its cluster UID from a configmap in kube.namespace so generated
+// resource names stay stable across restarts. It returns a nil provisioner
+// for --cloud-provider=none, so the status syncer publishes the addresses
+// selected by the --publish-* flags.
+func newCloudProvisioner(cfg *rest.Config) (cloudprovider.LBProvisioner, error) {
+	if cloudProviderName == "" || cloudProviderName == string(cloudprovider.None) {
+		return nil, nil
+	}
+
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kube clientset")
+	}
+	clusterUID, err := cloudprovider.GetClusterUID(kube, opts.KubeOptions.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine cluster uid")
+	}
+	return cloudprovider.New(cloudprovider.Name(cloudProviderName), clusterUID)
+}
+
 func runIngressController(cfg *rest.Config, store storage.Interface, stop <-chan struct{}) error {
-	ingressCtl, err := ingress.NewIngressController(cfg, store, opts.ConfigWatcherOptions.SyncFrequency, globalIngress)
+	cloud, err := newCloudProvisioner(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to set up cloud provider")
+	}
+
+	ingressCtl, err := ingress.NewIngressController(cfg, store, opts.ConfigWatcherOptions.SyncFrequency, globalIngress, ingressClassName, cloud)
 	if err != nil {
 		return errors.Wrap(err, "failed to create ingress controller")
 	}
 
-	if ingressServiceName != "" {
-		ingressSync, err := ingress.NewIngressSyncer(cfg, opts.ConfigWatcherOptions.SyncFrequency, stop, globalIngress, ingressServiceName)
-		if err != nil {
-			return errors.Wrap(err, "failed to start load balancer status syncer")
-		}
-		go func(stop <-chan struct{}) {
-			log.Printf("starting ingress status sync")
-			for {
-				select {
-				case err := <-ingressSync.Error():
-					log.Printf("ingress sync encountered error: %v", err)
-				case <-stop:
-					return
-				}
-			}
-		}(stop)
+	podName, podNamespace := ingress.PodEnvOptions()
+	publishOpts := ingress.PublishOptions{
+		UpdateStatus:     updateStatus,
+		PublishService:   publishService,
+		PublishAddresses: ingress.ParsePublishAddresses(publishAddress),
+		PodName:          podName,
+		PodNamespace:     podNamespace,
 	}
 
+	ingressSync, err := ingress.NewIngressSyncer(cfg, opts.ConfigWatcherOptions.SyncFrequency, stop, ingressCtl, publishOpts, cloud)
+	if err != nil {
+		return errors.Wrap(err, "failed to start load balancer status syncer")
+	}
+	go func(stop <-chan struct{}) {
+		log.Printf("starting ingress status sync")
+		for {
+			select {
+			case err := <-ingressSync.Error():
+				log.Printf("ingress sync encountered error: %v", err)
+			case <-stop:
+				return
+			}
+		}
+	}(stop)
+
 	go func(stop <-chan struct{}) {
 		log.Printf("starting ingress sync")
 		for {
@@ -134,6 +237,28 @@ func runIngressController(cfg *rest.Config, store storage.Interface, stop <-chan
 		}
 	}(stop)
 
+	if enableGatewayAPI {
+		gatewayCtl, err := gateway.NewController(cfg, store, opts.ConfigWatcherOptions.SyncFrequency)
+		if err != nil {
+			return errors.Wrap(err, "failed to create gateway api controller")
+		}
+		go func(stop <-chan struct{}) {
+			log.Printf("starting gateway api sync")
+			for {
+				select {
+				case err := <-gatewayCtl.Error():
+					log.Printf("gateway api controller encountered error: %v", err)
+				case <-stop:
+					return
+				}
+			}
+		}(stop)
+		go func() {
+			log.Printf("starting gateway api controller")
+			gatewayCtl.Run(stop)
+		}()
+	}
+
 	log.Printf("starting ingress controller")
 	ingressCtl.Run(stop)
 